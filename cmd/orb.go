@@ -3,27 +3,72 @@ package cmd
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
 	"io/ioutil"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
 
 	"github.com/CircleCI-Public/circleci-cli/client"
+	"github.com/CircleCI-Public/circleci-cli/pkg/httprecord"
+	"github.com/CircleCI-Public/circleci-cli/pkg/orbscan"
 	"github.com/pkg/errors"
 
 	"github.com/machinebox/graphql"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	yaml "gopkg.in/yaml.v2"
 )
 
 var orbPath string
 
+// scanSecrets and failOn back the --scan-secrets / --fail-on flags shared
+// by every command that loads orb.yml before sending it to the API.
+var scanSecrets bool
+var failOn string
+
+// orbListNamespace, orbListSearch, orbListCertified, orbListUncertified,
+// orbListLimit, orbListPageSize, orbListSort and orbListFormat back `orb
+// list`'s filtering, pagination and output flags.
+var orbListNamespace string
+var orbListSearch string
+var orbListCertified bool
+var orbListUncertified bool
+var orbListLimit int
+var orbListPageSize int
+var orbListSort string
+var orbListFormat string
+
+// outputFormat backs the global --output flag, shared by every command
+// that can report OrbErrors, so CI annotation tooling and IDE plugins can
+// consume `circleci orb validate` output without scraping free text.
+var outputFormat string
+
+// orbRefRegexp matches a namespace/orb@version reference, e.g.
+// "circleci/rollbar@1.0.1" or "circleci/rollbar@dev:my-branch".
+var orbRefRegexp = regexp.MustCompile(`^([\w-]+)/([\w-]+)@([\w.:-]+)$`)
+
+// orbSourceResponse is the shape of the `orbConfig: orb(orbRef: ...) {
+// sourceYaml, errors }` query shared by `orb source`, `orb info` and the
+// offline expander's single-orb fetch.
+type orbSourceResponse struct {
+	OrbConfig struct {
+		SourceYaml string
+		Errors     []gqlMessage
+	}
+}
+
 type orbConfigResponse struct {
 	OrbConfig struct {
 		Valid      bool
 		SourceYaml string
 		OutputYaml string
 
-		Errors []struct {
-			Message string
-		}
+		Errors []gqlMessage
 	}
 }
 
@@ -47,52 +92,213 @@ func newOrbCommand() *cobra.Command {
 		RunE:  expandOrb,
 	}
 
+	orbPackCommand := newOrbPackCommand()
+
+	orbPublishCommand := &cobra.Command{
+		Use:   "publish <namespace>/<orb>@<version>",
+		Short: "Publish a new version of an orb",
+		RunE:  publishOrb,
+		Args:  cobra.ExactArgs(1),
+	}
+
+	orbPromoteCommand := &cobra.Command{
+		Use:   "promote <namespace>/<orb>@<dev-version> <segment>",
+		Short: "Promote a development version of an orb to a semantic release, given a \"major\", \"minor\" or \"patch\" segment",
+		RunE:  promoteOrb,
+		Args:  cobra.ExactArgs(2),
+	}
+
+	orbSourceCommand := &cobra.Command{
+		Use:   "source <namespace>/<orb>@<version>",
+		Short: "Show the source of an orb",
+		RunE:  showOrbSource,
+		Args:  cobra.ExactArgs(1),
+	}
+
+	orbInfoCommand := &cobra.Command{
+		Use:   "info <namespace>/<orb>@<version>",
+		Short: "Show the meta-data of an orb",
+		RunE:  orbInfo,
+		Args:  cobra.ExactArgs(1),
+	}
+
+	orbNamespaceCreateCommand := &cobra.Command{
+		Use:   "create <namespace>",
+		Short: "Create an orb namespace",
+		RunE:  createOrbNamespace,
+		Args:  cobra.ExactArgs(1),
+	}
+
+	orbNamespaceCommand := &cobra.Command{
+		Use:   "namespace",
+		Short: "Operate on orb namespaces",
+	}
+	orbNamespaceCommand.AddCommand(orbNamespaceCreateCommand)
+
 	orbCommand := &cobra.Command{
 		Use:   "orb",
 		Short: "Operate on orbs",
 	}
 
+	orbCommand.PersistentFlags().StringVar(&outputFormat, "output", "text", "output format: text, json or sarif")
+
+	orbListCommand.Flags().StringVar(&orbListNamespace, "namespace", "", "only list orbs in this namespace")
+	orbListCommand.Flags().StringVar(&orbListSearch, "search", "", "only list orbs whose name matches this search string")
+	orbListCommand.Flags().BoolVar(&orbListCertified, "certified", false, "only list certified orbs")
+	orbListCommand.Flags().BoolVar(&orbListUncertified, "uncertified", false, "only list uncertified orbs")
+	orbListCommand.Flags().IntVar(&orbListLimit, "limit", 0, "stop after this many orbs (0 means no limit)")
+	orbListCommand.Flags().IntVar(&orbListPageSize, "page-size", 20, "number of orbs to fetch per GraphQL request")
+	orbListCommand.Flags().StringVar(&orbListSort, "sort", "name", "sort by \"name\", \"updated\" or \"downloads\"")
+	orbListCommand.Flags().StringVar(&orbListFormat, "format", "table", "output format: text, json, yaml or table")
 	orbCommand.AddCommand(orbListCommand)
 
 	orbValidateCommand.PersistentFlags().StringVarP(&orbPath, "path", "p", "orb.yml", "path to orb file")
+	orbValidateCommand.PersistentFlags().BoolVar(&scanSecrets, "scan-secrets", false, "scan the orb for secrets before sending it to the API")
+	orbValidateCommand.PersistentFlags().StringVar(&failOn, "fail-on", "verified", "fail the scan on \"verified\" or \"unverified\" findings")
 	orbCommand.AddCommand(orbValidateCommand)
 
 	orbExpandCommand.PersistentFlags().StringVarP(&orbPath, "path", "p", "orb.yml", "path to orb file")
+	orbExpandCommand.PersistentFlags().BoolVar(&scanSecrets, "scan-secrets", false, "scan the orb for secrets before sending it to the API")
+	orbExpandCommand.PersistentFlags().StringVar(&failOn, "fail-on", "verified", "fail the scan on \"verified\" or \"unverified\" findings")
+	orbExpandCommand.PersistentFlags().BoolVar(&offline, "offline", false, "expand the orb entirely on the client, without a GraphQL round-trip (fails if an inlined job/command/executor has an orb parameter that can't be substituted)")
+	orbExpandCommand.PersistentFlags().BoolVar(&verifyAgainstServer, "verify-against-server", false, "also run the normal server-side expansion and semantically compare it against --offline's output")
 	orbCommand.AddCommand(orbExpandCommand)
 
+	orbCommand.AddCommand(orbPackCommand)
+
+	orbPublishCommand.PersistentFlags().StringVarP(&orbPath, "path", "p", "orb.yml", "path to orb file")
+	orbPublishCommand.PersistentFlags().BoolVar(&scanSecrets, "scan-secrets", false, "scan the orb for secrets before sending it to the API")
+	orbPublishCommand.PersistentFlags().StringVar(&failOn, "fail-on", "verified", "fail the scan on \"verified\" or \"unverified\" findings")
+	orbCommand.AddCommand(orbPublishCommand)
+
+	orbCommand.AddCommand(orbPromoteCommand)
+	orbCommand.AddCommand(orbSourceCommand)
+	orbCommand.AddCommand(orbInfoCommand)
+	orbCommand.AddCommand(orbNamespaceCommand)
+
 	return orbCommand
 }
 
+// parseOrbRef splits a "namespace/orb@version" reference into its three
+// parts. It is shared by every subcommand that accepts an orb reference on
+// the command line, so that the accepted syntax and error message stay
+// consistent across `publish`, `promote`, `source` and `info`.
+func parseOrbRef(ref string) (namespace string, orb string, version string, err error) {
+	matches := orbRefRegexp.FindStringSubmatch(ref)
+	if matches == nil {
+		return "", "", "", fmt.Errorf("Expected an orb reference of the form <namespace>/<orb>@<version>, got %q", ref)
+	}
+
+	return matches[1], matches[2], matches[3], nil
+}
+
+// graphQLRunner is satisfied by both client.NewClient's return type and a
+// bare *graphql.Client, so newOrbGraphQLClient can hand back either one
+// depending on whether HAR recording is enabled.
+type graphQLRunner interface {
+	Run(ctx context.Context, req *graphql.Request, resp interface{}) error
+}
+
+// newOrbGraphQLClient returns the GraphQL client orb commands should issue
+// requests through, along with a func to call once the caller is done with
+// it. In the common case that's the shared client.NewClient wrapper; when
+// CIRCLECI_CLI_HTTPRECORD_MODE is set to "record" or "replay" it instead
+// returns a client wired through pkg/httprecord, so orb command tests can
+// run against a golden HAR fixture instead of firing real HTTP requests.
+func newOrbGraphQLClient() (graphQLRunner, func() error, error) {
+	mode, harPath := httprecordModeFromEnv()
+	noop := func() error { return nil }
+
+	if mode == httprecord.Off {
+		return client.NewClient(viper.GetString("endpoint"), Logger), noop, nil
+	}
+
+	transport, err := httprecord.NewTransport(mode, harPath, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	gqlClient := graphql.NewClient(viper.GetString("endpoint"), graphql.WithHTTPClient(&http.Client{Transport: transport}))
+
+	save := noop
+	if recorder, ok := transport.(interface{ Save() error }); ok {
+		save = recorder.Save
+	}
+
+	return gqlClient, save, nil
+}
+
+func httprecordModeFromEnv() (httprecord.Mode, string) {
+	switch os.Getenv("CIRCLECI_CLI_HTTPRECORD_MODE") {
+	case "record":
+		return httprecord.Record, os.Getenv("CIRCLECI_CLI_HTTPRECORD_PATH")
+	case "replay":
+		return httprecord.Replay, os.Getenv("CIRCLECI_CLI_HTTPRECORD_PATH")
+	default:
+		return httprecord.Off, ""
+	}
+}
+
+// orbListEntry is a single orb as returned by the ListOrbs query, extended
+// beyond its name with enough metadata (versions, certification, usage,
+// last update) to make `orb list` useful for discovery rather than just an
+// existence check.
+type orbListEntry struct {
+	// Cursor is GraphQL pagination state, not part of the orb's identity,
+	// so it's excluded from --format=json/yaml output.
+	Cursor string `json:"-" yaml:"-"`
+	Node   struct {
+		Name     string `json:"name" yaml:"name"`
+		Versions []struct {
+			Version string `json:"version" yaml:"version"`
+		} `json:"versions" yaml:"versions"`
+		IsPrivate  bool   `json:"isPrivate" yaml:"isPrivate"`
+		Certified  bool   `json:"certified" yaml:"certified"`
+		UpdatedAt  string `json:"updatedAt" yaml:"updatedAt"`
+		Statistics struct {
+			Last30DaysBuildCount int `json:"last30DaysBuildCount" yaml:"last30DaysBuildCount"`
+		} `json:"statistics" yaml:"statistics"`
+	} `json:"node" yaml:"node"`
+}
+
+func (e orbListEntry) latestVersion() string {
+	if len(e.Node.Versions) == 0 {
+		return "none"
+	}
+	return e.Node.Versions[0].Version
+}
+
+func (e orbListEntry) certified() bool {
+	return e.Node.Certified
+}
+
 func listOrbs(cmd *cobra.Command, args []string) error {
 
 	ctx := context.Background()
 
-	// Define a structure that matches the result of the GQL
-	// query, so that we can use mapstructure to convert from
-	// nested maps to a strongly typed struct.
 	type orbList struct {
 		Orbs struct {
 			TotalCount int
-			Edges      []struct {
-				Cursor string
-				Node   struct {
-					Name string
-				}
-			}
-			PageInfo struct {
+			Edges      []orbListEntry
+			PageInfo   struct {
 				HasNextPage bool
 			}
 		}
 	}
 
 	request := graphql.NewRequest(`
-query ListOrbs ($after: String!) {
-  orbs(first: 20, after: $after) {
+query ListOrbs ($after: String!, $namespace: String, $query: String, $certifiedOnly: Boolean, $pageSize: Int!) {
+  orbs(first: $pageSize, after: $after, namespace: $namespace, query: $query, certifiedOnly: $certifiedOnly) {
 	totalCount,
     edges {
       cursor,
       node {
-        name
+        name,
+        versions(count: 1) { version },
+        isPrivate,
+        certified,
+        updatedAt,
+        statistics { last30DaysBuildCount }
       }
     }
     pageInfo {
@@ -102,34 +308,112 @@ query ListOrbs ($after: String!) {
 }
 	`)
 
-	client := client.NewClient(viper.GetString("endpoint"), Logger)
+	request.Var("pageSize", orbListPageSize)
+	if orbListNamespace != "" {
+		request.Var("namespace", orbListNamespace)
+	}
+	if orbListSearch != "" {
+		request.Var("query", orbListSearch)
+	}
+	if orbListCertified && !orbListUncertified {
+		request.Var("certifiedOnly", true)
+	} else if orbListUncertified && !orbListCertified {
+		request.Var("certifiedOnly", false)
+	}
+
+	gqlClient, closeClient, err := newOrbGraphQLClient()
+	if err != nil {
+		return errors.Wrap(err, "Unable to construct GraphQL client")
+	}
+	defer closeClient()
 
 	var result orbList
+	var orbs []orbListEntry
 	currentCursor := ""
 
 	for {
 		request.Var("after", currentCursor)
-		err := client.Run(ctx, request, &result)
+		err := gqlClient.Run(ctx, request, &result)
 
 		if err != nil {
 			return errors.Wrap(err, "GraphQL query failed")
 		}
 
-		// Debug logging of result fields.
-		// Logger.Prettyify(result)
+		orbs = append(orbs, result.Orbs.Edges...)
 
 		for i := range result.Orbs.Edges {
-			edge := result.Orbs.Edges[i]
-			currentCursor = edge.Cursor
-			Logger.Infof("Orb: %s\n", edge.Node.Name)
+			currentCursor = result.Orbs.Edges[i].Cursor
 		}
 
 		if !result.Orbs.PageInfo.HasNextPage {
 			break
 		}
 	}
-	return nil
 
+	// Sort before truncating: --limit must keep the top N by --sort, not
+	// just the first N the API happened to return before the cap kicked
+	// in.
+	sortOrbListEntries(orbs, orbListSort)
+
+	if orbListLimit > 0 && len(orbs) > orbListLimit {
+		orbs = orbs[:orbListLimit]
+	}
+
+	return printOrbList(orbs, orbListFormat)
+}
+
+// sortOrbListEntries sorts orbs in place by the field named by sortBy
+// ("name", "updated" or "downloads"). An unrecognized sortBy leaves the
+// API's own ordering untouched.
+func sortOrbListEntries(orbs []orbListEntry, sortBy string) {
+	switch sortBy {
+	case "updated":
+		sort.Slice(orbs, func(i, j int) bool { return orbs[i].Node.UpdatedAt > orbs[j].Node.UpdatedAt })
+	case "downloads":
+		sort.Slice(orbs, func(i, j int) bool {
+			return orbs[i].Node.Statistics.Last30DaysBuildCount > orbs[j].Node.Statistics.Last30DaysBuildCount
+		})
+	case "name":
+		sort.Slice(orbs, func(i, j int) bool { return orbs[i].Node.Name < orbs[j].Node.Name })
+	}
+}
+
+// printOrbList renders orbs in the format named by format: "text" (name
+// only, the original behavior), "json", "yaml" or the default "table".
+func printOrbList(orbs []orbListEntry, format string) error {
+	switch format {
+	case "text":
+		for _, o := range orbs {
+			Logger.Infof("Orb: %s\n", o.Node.Name)
+		}
+		return nil
+	case "json":
+		out, err := json.MarshalIndent(orbs, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, "Unable to marshal orb list as JSON")
+		}
+		Logger.Info(string(out))
+		return nil
+	case "yaml":
+		out, err := yaml.Marshal(orbs)
+		if err != nil {
+			return errors.Wrap(err, "Unable to marshal orb list as YAML")
+		}
+		Logger.Info(string(out))
+		return nil
+	default:
+		var buffer bytes.Buffer
+		table := tabwriter.NewWriter(&buffer, 0, 2, 2, ' ', 0)
+		fmt.Fprintln(table, "NAME\tLATEST\tCERTIFIED\tUPDATED")
+		for _, o := range orbs {
+			fmt.Fprintf(table, "%s\t%s\t%t\t%s\n", o.Node.Name, o.latestVersion(), o.certified(), o.Node.UpdatedAt)
+		}
+		if err := table.Flush(); err != nil {
+			return errors.Wrap(err, "Unable to render orb list table")
+		}
+		Logger.Info(buffer.String())
+		return nil
+	}
 }
 
 func loadOrbYaml(path string) (string, error) {
@@ -140,46 +424,125 @@ func loadOrbYaml(path string) (string, error) {
 		return "", errors.Wrapf(err, "Could not load orb file at %s", path)
 	}
 
+	if scanSecrets {
+		if err := scanOrbYamlForSecrets(path, orb); err != nil {
+			return "", err
+		}
+	}
+
 	return string(orb), nil
 }
 
-func (response orbConfigResponse) processErrors() error {
+// scanOrbYamlForSecrets runs the orbscan detectors over an orb's source and
+// returns an error describing every finding that meets the --fail-on
+// threshold. Orbs are published globally, so a credential caught here never
+// reaches the API at all.
+func scanOrbYamlForSecrets(path string, orb []byte) error {
+	scanner := orbscan.NewScanner()
+
+	findings, err := scanner.Detect(bytes.NewReader(orb))
+	if err != nil {
+		return errors.Wrap(err, "Unable to scan orb for secrets")
+	}
+
+	ctx := context.Background()
+	var blocking []orbscan.Finding
+	for _, f := range findings {
+		// --fail-on=unverified blocks on every finding regardless of
+		// verification status, so skip the live Verify call rather than
+		// firing an unasked-for request with the matched candidate
+		// secret.
+		if failOn != "unverified" {
+			f.Verified = scanner.Verify(ctx, f)
+		}
+		if failOn == "unverified" || f.Verified {
+			blocking = append(blocking, f)
+		}
+	}
+
+	if len(blocking) == 0 {
+		return nil
+	}
+
 	var buffer bytes.Buffer
+	buffer.WriteString(fmt.Sprintf("found %d potential secret(s) in %s:\n", len(blocking), path))
+	for _, f := range blocking {
+		status := "unverified"
+		if f.Verified {
+			status = "verified"
+		}
+		buffer.WriteString(fmt.Sprintf("-- [%s] %s:%d: %s\n", status, path, f.Line, f.RuleID))
+	}
+
+	return errors.New(strings.TrimRight(buffer.String(), "\n"))
+}
 
-	buffer.WriteString("\n")
-	for i := range response.OrbConfig.Errors {
-		buffer.WriteString("-- ")
-		buffer.WriteString(response.OrbConfig.Errors[i].Message)
-		buffer.WriteString(",\n")
+// toOrbErrors classifies every raw GraphQL error on the response into a
+// structured OrbError, so callers can branch on OrbError.Code instead of
+// pattern-matching free text.
+func (response orbConfigResponse) toOrbErrors() []OrbError {
+	return classifyErrorMessages(response.OrbConfig.Errors)
+}
+
+func (response orbConfigResponse) processErrors() error {
+	return errorsFromMessages(response.OrbConfig.Errors)
+}
+
+// gqlMessage is the shape every GraphQL payload in this file uses for its
+// `errors { message }` selection.
+type gqlMessage struct {
+	Message string
+}
+
+// classifyErrorMessages classifies a raw `errors { message }` selection
+// into structured OrbErrors, so callers can branch on OrbError.Code instead
+// of pattern-matching free text.
+func classifyErrorMessages(messages []gqlMessage) []OrbError {
+	orbErrors := make([]OrbError, len(messages))
+	for i := range messages {
+		orbErrors[i] = classifyError(messages[i].Message)
 	}
+	return orbErrors
+}
 
-	return errors.New(buffer.String())
+// errorsFromMessages is the shared "turn a GraphQL payload's errors[] into
+// a single Go error" path used by every orb mutation/query in this file.
+func errorsFromMessages(messages []gqlMessage) error {
+	formatted, err := formatOrbErrors(classifyErrorMessages(messages))
+	if err != nil {
+		return errors.Wrap(err, "Unable to format orb errors")
+	}
+
+	return errors.New(formatted)
 }
 
 func orbValidateQuery(ctx context.Context) (*orbConfigResponse, error) {
 
-	query := `
-		query ValidateOrb ($orb: String!) {
-			orbConfig(orbYaml: $orb) {
-				valid,
-				errors { message },
-				sourceYaml,
-				outputYaml
-			}
-		}`
-
 	orb, err := loadOrbYaml(orbPath)
 	if err != nil {
 		return nil, err
 	}
 
-	variables := map[string]string{
-		"orb": orb,
+	request := graphql.NewRequest(`
+query ValidateOrb ($orb: String!) {
+  orbConfig(orbYaml: $orb) {
+    valid,
+    errors { message },
+    sourceYaml,
+    outputYaml
+  }
+}
+	`)
+	request.Var("orb", orb)
+
+	gqlClient, closeClient, err := newOrbGraphQLClient()
+	if err != nil {
+		return nil, errors.Wrap(err, "Unable to construct GraphQL client")
 	}
+	defer closeClient()
 
 	var response orbConfigResponse
-	err = queryAPI(ctx, query, variables, &response)
-	if err != nil {
+	if err := gqlClient.Run(ctx, request, &response); err != nil {
 		return nil, errors.Wrap(err, "Unable to validate orb")
 	}
 
@@ -202,9 +565,229 @@ func validateOrb(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func publishOrb(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	namespace, orb, version, err := parseOrbRef(args[0])
+	if err != nil {
+		return err
+	}
+
+	orbYaml, err := loadOrbYaml(orbPath)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		mutation PublishOrb ($namespace: String!, $orb: String!, $version: String!, $config: String!) {
+			publishOrb(namespace: $namespace, orb: $orb, version: $version, orbYaml: $config) {
+				orb {
+					version
+				}
+				errors { message }
+			}
+		}`
+
+	variables := map[string]string{
+		"namespace": namespace,
+		"orb":       orb,
+		"version":   version,
+		"config":    orbYaml,
+	}
+
+	var response struct {
+		PublishOrb struct {
+			Orb struct {
+				Version string
+			}
+			Errors []gqlMessage
+		}
+	}
+	err = queryAPI(ctx, query, variables, &response)
+	if err != nil {
+		return errors.Wrap(err, "Unable to publish orb")
+	}
+
+	if len(response.PublishOrb.Errors) > 0 {
+		return errorsFromMessages(response.PublishOrb.Errors)
+	}
+
+	Logger.Infof("Orb %s/%s@%s published", namespace, orb, response.PublishOrb.Orb.Version)
+	return nil
+}
+
+func promoteOrb(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	namespace, orb, devVersion, err := parseOrbRef(args[0])
+	if err != nil {
+		return err
+	}
+	segment := args[1]
+
+	query := `
+		mutation PromoteOrb ($namespace: String!, $orb: String!, $devVersion: String!, $segment: String!) {
+			promoteOrb(namespace: $namespace, orb: $orb, devVersion: $devVersion, segment: $segment) {
+				orb {
+					version
+				}
+				errors { message }
+			}
+		}`
+
+	variables := map[string]string{
+		"namespace":  namespace,
+		"orb":        orb,
+		"devVersion": devVersion,
+		"segment":    segment,
+	}
+
+	var response struct {
+		PromoteOrb struct {
+			Orb struct {
+				Version string
+			}
+			Errors []gqlMessage
+		}
+	}
+	err = queryAPI(ctx, query, variables, &response)
+	if err != nil {
+		return errors.Wrap(err, "Unable to promote orb")
+	}
+
+	if len(response.PromoteOrb.Errors) > 0 {
+		return errorsFromMessages(response.PromoteOrb.Errors)
+	}
+
+	Logger.Infof("Orb %s/%s@%s promoted from %s", namespace, orb, response.PromoteOrb.Orb.Version, devVersion)
+	return nil
+}
+
+func createOrbNamespace(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	namespace := args[0]
+
+	query := `
+		mutation CreateNamespace ($namespace: String!) {
+			createOrbNamespace(namespace: $namespace) {
+				namespace { name }
+				errors { message }
+			}
+		}`
+
+	variables := map[string]string{
+		"namespace": namespace,
+	}
+
+	var response struct {
+		CreateOrbNamespace struct {
+			Namespace struct {
+				Name string
+			}
+			Errors []gqlMessage
+		}
+	}
+	err := queryAPI(ctx, query, variables, &response)
+	if err != nil {
+		return errors.Wrap(err, "Unable to create namespace")
+	}
+
+	if len(response.CreateOrbNamespace.Errors) > 0 {
+		return errorsFromMessages(response.CreateOrbNamespace.Errors)
+	}
+
+	Logger.Infof("Namespace %s created", namespace)
+	return nil
+}
+
+func showOrbSource(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	namespace, orb, version, err := parseOrbRef(args[0])
+	if err != nil {
+		return err
+	}
+
+	query := `
+		query OrbSource ($orbRef: String!) {
+			orbConfig: orb(orbRef: $orbRef) {
+				sourceYaml
+				errors { message }
+			}
+		}`
+
+	variables := map[string]string{
+		"orbRef": fmt.Sprintf("%s/%s@%s", namespace, orb, version),
+	}
+
+	var response orbSourceResponse
+	err = queryAPI(ctx, query, variables, &response)
+	if err != nil {
+		return errors.Wrap(err, "Unable to fetch orb source")
+	}
+
+	if len(response.OrbConfig.Errors) > 0 {
+		return errorsFromMessages(response.OrbConfig.Errors)
+	}
+
+	Logger.Info(response.OrbConfig.SourceYaml)
+	return nil
+}
+
+func orbInfo(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	namespace, orb, version, err := parseOrbRef(args[0])
+	if err != nil {
+		return err
+	}
+
+	query := `
+		query OrbInfo ($orbRef: String!) {
+			orbConfig: orb(orbRef: $orbRef) {
+				sourceYaml
+				errors { message }
+			}
+		}`
+
+	variables := map[string]string{
+		"orbRef": fmt.Sprintf("%s/%s@%s", namespace, orb, version),
+	}
+
+	var response orbSourceResponse
+	err = queryAPI(ctx, query, variables, &response)
+	if err != nil {
+		return errors.Wrap(err, "Unable to fetch orb info")
+	}
+
+	if len(response.OrbConfig.Errors) > 0 {
+		return errorsFromMessages(response.OrbConfig.Errors)
+	}
+
+	Logger.Infof("Orb: %s/%s@%s\n", namespace, orb, version)
+	Logger.Info(response.OrbConfig.SourceYaml)
+	return nil
+}
+
 func expandOrb(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 
+	if offline {
+		out, err := offlineExpandOrb(ctx, orbPath)
+		if err != nil {
+			return err
+		}
+
+		if verifyAgainstServer {
+			if err := diffAgainstServer(ctx, out); err != nil {
+				return err
+			}
+		}
+
+		Logger.Info(out)
+		return nil
+	}
+
 	response, err := orbValidateQuery(ctx)
 
 	if err != nil {
@@ -217,4 +800,4 @@ func expandOrb(cmd *cobra.Command, args []string) error {
 
 	Logger.Info(response.OrbConfig.OutputYaml)
 	return nil
-}
\ No newline at end of file
+}
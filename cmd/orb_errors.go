@@ -0,0 +1,217 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ErrorCode is a stable, machine-readable identifier for a class of orb
+// configuration error, independent of the free-text message the GraphQL
+// API happens to return for it.
+type ErrorCode string
+
+const (
+	// ENoSchema means the orb source did not conform to the orb schema.
+	ENoSchema ErrorCode = "ENOSCHEMA"
+	// EBadParam means a job/command parameter was used with the wrong
+	// type, or an unknown parameter was passed.
+	EBadParam ErrorCode = "EBADPARAM"
+	// EUnknownOrb means a referenced orb could not be resolved.
+	EUnknownOrb ErrorCode = "EUNKNOWNORB"
+	// ECycle means orbs/jobs/commands reference each other in a cycle.
+	ECycle ErrorCode = "ECYCLE"
+	// EDeprecated means the orb uses a deprecated element.
+	EDeprecated ErrorCode = "EDEPRECATED"
+	// EUnknown is used when no classifier rule matches; it should never
+	// be a terminal state, only a prompt to extend classifyError.
+	EUnknown ErrorCode = "EUNKNOWN"
+)
+
+// OrbError is a structured, machine-readable rendering of a single error
+// returned by the orbConfig GraphQL field. The API's errors field is just
+// `{ message }` — it has no structured path/line of its own — so Path is a
+// best-effort YAML key path extracted from the message text by
+// extractPath, and is empty whenever the message doesn't mention one.
+type OrbError struct {
+	Code         ErrorCode `json:"code"`
+	Message      string    `json:"message"`
+	MinorMessage string    `json:"minorMessage,omitempty"`
+	Details      string    `json:"details,omitempty"`
+	Path         string    `json:"path,omitempty"`
+}
+
+// classifierRule pairs a substring to look for in a raw GraphQL error
+// message with the ErrorCode it implies. Rules are tried in order, so more
+// specific substrings should be listed before more general ones.
+var classifierRules = []struct {
+	substring string
+	code      ErrorCode
+}{
+	{"does not match schema", ENoSchema},
+	{"schema", ENoSchema},
+	{"unknown parameter", EBadParam},
+	{"wrong type for parameter", EBadParam},
+	{"parameter", EBadParam},
+	{"could not be found", EUnknownOrb},
+	{"unknown orb", EUnknownOrb},
+	{"cyclic", ECycle},
+	{"cycle", ECycle},
+	{"deprecated", EDeprecated},
+}
+
+// classifyError maps a raw GraphQL error message to a stable ErrorCode by
+// matching against classifierRules, and attaches whatever YAML key path
+// extractPath can find in the message. Messages that match nothing get
+// EUnknown rather than being dropped, so callers always have a code to
+// branch on.
+func classifyError(message string) OrbError {
+	lower := strings.ToLower(message)
+	path := extractPath(message)
+
+	for _, rule := range classifierRules {
+		if strings.Contains(lower, rule.substring) {
+			return OrbError{Code: rule.code, Message: message, Path: path}
+		}
+	}
+
+	return OrbError{Code: EUnknown, Message: message, Path: path}
+}
+
+// pathPatterns matches the ways the orbConfig error messages observed so
+// far point at a YAML key path, e.g. `in "jobs.build.steps[2]"` or
+// `at 'commands.checkout'`. The matched path is reported as-is; callers
+// that want a URI (e.g. formatOrbErrorsSarif) are responsible for turning
+// it into one.
+var pathPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)\bin\s+"([^"]+)"`),
+	regexp.MustCompile(`(?i)\bin\s+'([^']+)'`),
+	regexp.MustCompile(`(?i)\bat\s+"([^"]+)"`),
+	regexp.MustCompile(`(?i)\bat\s+'([^']+)'`),
+}
+
+// extractPath pulls a best-effort YAML key path out of a raw GraphQL error
+// message. The orbConfig field's errors have no structured path of their
+// own, so this is a heuristic over message text, not a guarantee: it
+// returns "" whenever none of pathPatterns match, and callers (and SARIF
+// output) must treat a missing Path as "location unavailable", not as an
+// error.
+func extractPath(message string) string {
+	for _, pattern := range pathPatterns {
+		if match := pattern.FindStringSubmatch(message); match != nil {
+			return match[1]
+		}
+	}
+
+	return ""
+}
+
+// sarifLog is the minimal subset of the SARIF 2.1.0 schema needed to report
+// orb validation errors as CI annotations / IDE diagnostics.
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID string `json:"id"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// formatOrbErrors renders errs in the format selected by the global
+// --output flag (text, json or sarif).
+func formatOrbErrors(errs []OrbError) (string, error) {
+	switch outputFormat {
+	case "json":
+		out, err := json.MarshalIndent(errs, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(out), nil
+	case "sarif":
+		return formatOrbErrorsSarif(errs)
+	default:
+		var buffer strings.Builder
+		buffer.WriteString("\n")
+		for _, e := range errs {
+			buffer.WriteString(fmt.Sprintf("-- [%s] %s,\n", e.Code, e.Message))
+		}
+		return buffer.String(), nil
+	}
+}
+
+// formatOrbErrorsSarif renders errs as SARIF. Results whose Path couldn't
+// be extracted from the underlying message are emitted with no locations
+// rather than a fabricated one, per the SARIF spec's "locations" being
+// optional.
+func formatOrbErrorsSarif(errs []OrbError) (string, error) {
+	results := make([]sarifResult, len(errs))
+	rules := make([]sarifRule, len(errs))
+
+	for i, e := range errs {
+		results[i] = sarifResult{
+			RuleID:  string(e.Code),
+			Message: sarifMessage{Text: e.Message},
+		}
+		if e.Path != "" {
+			results[i].Locations = []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation{URI: e.Path},
+				},
+			}}
+		}
+		rules[i] = sarifRule{ID: string(e.Code)}
+	}
+
+	log := sarifLog{
+		Schema:  "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []sarifRun{{
+			Tool:    sarifTool{Driver: sarifDriver{Name: "circleci-orb-validate", Rules: rules}},
+			Results: results,
+		}},
+	}
+
+	out, err := json.MarshalIndent(log, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
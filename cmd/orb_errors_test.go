@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestClassifyError(t *testing.T) {
+	cases := []struct {
+		name    string
+		message string
+		want    ErrorCode
+	}{
+		{
+			name:    "schema mismatch",
+			message: `config does not match schema in "jobs.build"`,
+			want:    ENoSchema,
+		},
+		{
+			name:    "generic schema mention falls back to ENoSchema",
+			message: "invalid schema for orb",
+			want:    ENoSchema,
+		},
+		{
+			name:    "unknown parameter is more specific than parameter",
+			message: `unknown parameter "foo" in "jobs.build"`,
+			want:    EBadParam,
+		},
+		{
+			name:    "wrong type for parameter is more specific than parameter",
+			message: `wrong type for parameter "foo" at 'jobs.build'`,
+			want:    EBadParam,
+		},
+		{
+			name:    "generic parameter mention falls back to EBadParam",
+			message: "parameter foo is required",
+			want:    EBadParam,
+		},
+		{
+			name:    "orb could not be found",
+			message: `orb "circleci/rollbar@1.0.0" could not be found`,
+			want:    EUnknownOrb,
+		},
+		{
+			name:    "unknown orb",
+			message: "unknown orb referenced",
+			want:    EUnknownOrb,
+		},
+		{
+			name:    "cyclic reference",
+			message: "cyclic dependency detected",
+			want:    ECycle,
+		},
+		{
+			name:    "cycle without cyclic",
+			message: "orbs form a cycle",
+			want:    ECycle,
+		},
+		{
+			name:    "deprecated element",
+			message: "this orb element is deprecated",
+			want:    EDeprecated,
+		},
+		{
+			name:    "no rule matches falls back to EUnknown",
+			message: "something went completely sideways",
+			want:    EUnknown,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := classifyError(c.message)
+			if got.Code != c.want {
+				t.Errorf("classifyError(%q).Code = %s, want %s", c.message, got.Code, c.want)
+			}
+			if got.Message != c.message {
+				t.Errorf("classifyError(%q).Message = %q, want %q", c.message, got.Message, c.message)
+			}
+		})
+	}
+}
+
+func TestExtractPath(t *testing.T) {
+	cases := []struct {
+		name    string
+		message string
+		want    string
+	}{
+		{
+			name:    `in "double quotes"`,
+			message: `unknown parameter "foo" in "jobs.build.steps[2]"`,
+			want:    "jobs.build.steps[2]",
+		},
+		{
+			name:    "in 'single quotes'",
+			message: "unknown parameter 'foo' in 'commands.checkout'",
+			want:    "commands.checkout",
+		},
+		{
+			name:    `at "double quotes"`,
+			message: `wrong type for parameter at "jobs.build"`,
+			want:    "jobs.build",
+		},
+		{
+			name:    "at 'single quotes'",
+			message: "wrong type for parameter at 'jobs.build'",
+			want:    "jobs.build",
+		},
+		{
+			name:    "no path in message",
+			message: "something went completely sideways",
+			want:    "",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := extractPath(c.message); got != c.want {
+				t.Errorf("extractPath(%q) = %q, want %q", c.message, got, c.want)
+			}
+		})
+	}
+}
+
+// TestFormatOrbErrorsSarif asserts the shape of the SARIF output: one
+// result/rule pair per OrbError, with a location only for errors whose
+// Path was extracted.
+func TestFormatOrbErrorsSarif(t *testing.T) {
+	errs := []OrbError{
+		{Code: ENoSchema, Message: "config does not match schema", Path: "jobs.build"},
+		{Code: EUnknown, Message: "something went completely sideways"},
+	}
+
+	out, err := formatOrbErrorsSarif(errs)
+	if err != nil {
+		t.Fatalf("formatOrbErrorsSarif() returned error: %s", err)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal([]byte(out), &log); err != nil {
+		t.Fatalf("output is not valid JSON: %s", err)
+	}
+
+	if log.Version != "2.1.0" {
+		t.Errorf("Version = %q, want %q", log.Version, "2.1.0")
+	}
+	if len(log.Runs) != 1 {
+		t.Fatalf("len(Runs) = %d, want 1", len(log.Runs))
+	}
+
+	run := log.Runs[0]
+	if len(run.Results) != len(errs) {
+		t.Fatalf("len(Results) = %d, want %d", len(run.Results), len(errs))
+	}
+	if len(run.Tool.Driver.Rules) != len(errs) {
+		t.Fatalf("len(Rules) = %d, want %d", len(run.Tool.Driver.Rules), len(errs))
+	}
+
+	if run.Results[0].RuleID != string(ENoSchema) {
+		t.Errorf("Results[0].RuleID = %q, want %q", run.Results[0].RuleID, ENoSchema)
+	}
+	if len(run.Results[0].Locations) != 1 {
+		t.Fatalf("Results[0] should have a location when Path is set, got %d", len(run.Results[0].Locations))
+	}
+	if run.Results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI != "jobs.build" {
+		t.Errorf("Results[0] location URI = %q, want %q", run.Results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI, "jobs.build")
+	}
+
+	if len(run.Results[1].Locations) != 0 {
+		t.Errorf("Results[1] should have no location when Path is empty, got %d", len(run.Results[1].Locations))
+	}
+}
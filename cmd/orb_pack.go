@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+
+	"github.com/CircleCI-Public/circleci-cli/pkg/orbpack"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// offline and verifyAgainstServer back the `orb expand --offline
+// --verify-against-server` flags.
+var offline bool
+var verifyAgainstServer bool
+
+func newOrbPackCommand() *cobra.Command {
+	orbPackCommand := &cobra.Command{
+		Use:   "pack",
+		Short: "Expand an orb.yml entirely on the client, without a GraphQL round-trip",
+		Long: `Expand an orb.yml entirely on the client, without a GraphQL round-trip.
+
+This does not perform parameter substitution: a referenced orb's job,
+command and executor bodies are inlined as-is, so any "<< parameters.* >>"
+placeholder they contain would otherwise be left unresolved rather than
+filled in with the value passed at the orbs:/job-invocation call site.
+Rather than emit that broken config, this command fails with an error
+naming the unresolved placeholder. Most published orbs take parameters,
+so expect this on anything beyond a parameterless orb until parameter
+substitution is implemented.`,
+		RunE: packOrb,
+	}
+	orbPackCommand.PersistentFlags().StringVarP(&orbPath, "path", "p", "orb.yml", "path to orb file")
+
+	return orbPackCommand
+}
+
+func packOrb(cmd *cobra.Command, args []string) error {
+	out, err := offlineExpandOrb(context.Background(), orbPath)
+	if err != nil {
+		return err
+	}
+
+	Logger.Info(out)
+	return nil
+}
+
+// offlineExpandOrb inlines orb.yml's `orbs:` stanza using only local state:
+// the on-disk cache under orbCacheDir, falling back to a single orbSource
+// fetch per referenced orb the first time it's seen.
+func offlineExpandOrb(ctx context.Context, path string) (string, error) {
+	source, err := loadOrbYaml(path)
+	if err != nil {
+		return "", err
+	}
+
+	return orbpack.Expand(source, func(ref string) (string, error) {
+		return resolveOrbRefOffline(ctx, ref)
+	})
+}
+
+// resolveOrbRefOffline returns the source YAML for ref, preferring the
+// on-disk orb cache (~/.circleci/orb-cache/<namespace>/<orb>/<version>.yml)
+// and falling back to a single orbSource GraphQL fetch, which it then
+// writes back to the cache for next time.
+func resolveOrbRefOffline(ctx context.Context, ref string) (string, error) {
+	namespace, orb, version, err := parseOrbRef(ref)
+	if err != nil {
+		return "", err
+	}
+
+	cachePath, err := orbCachePath(namespace, orb, version)
+	if err != nil {
+		return "", err
+	}
+
+	if cached, err := ioutil.ReadFile(cachePath); err == nil {
+		return string(cached), nil
+	}
+
+	source, err := fetchOrbSource(ctx, namespace, orb, version)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(cachePath), 0755); err != nil {
+		return "", errors.Wrapf(err, "creating orb cache directory for %s", ref)
+	}
+
+	if err := ioutil.WriteFile(cachePath, []byte(source), 0644); err != nil {
+		return "", errors.Wrapf(err, "writing orb cache entry for %s", ref)
+	}
+
+	return source, nil
+}
+
+// orbCachePath returns the on-disk location a resolved orb's source YAML
+// is cached at.
+func orbCachePath(namespace string, orb string, version string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", errors.Wrap(err, "Unable to determine home directory")
+	}
+
+	return filepath.Join(home, ".circleci", "orb-cache", namespace, orb, version+".yml"), nil
+}
+
+// fetchOrbSource fetches an orb's source YAML via the same orbSource query
+// that backs `orb source`.
+func fetchOrbSource(ctx context.Context, namespace string, orb string, version string) (string, error) {
+	query := `
+		query OrbSource ($orbRef: String!) {
+			orbConfig: orb(orbRef: $orbRef) {
+				sourceYaml
+				errors { message }
+			}
+		}`
+
+	variables := map[string]string{
+		"orbRef": fmt.Sprintf("%s/%s@%s", namespace, orb, version),
+	}
+
+	var response orbSourceResponse
+	if err := queryAPI(ctx, query, variables, &response); err != nil {
+		return "", errors.Wrapf(err, "Unable to fetch source for %s/%s@%s", namespace, orb, version)
+	}
+
+	if len(response.OrbConfig.Errors) > 0 {
+		return "", errorsFromMessages(response.OrbConfig.Errors)
+	}
+
+	return response.OrbConfig.SourceYaml, nil
+}
+
+// diffAgainstServer runs the normal server-side expansion and reports
+// whether it differs from the locally computed output, for
+// --verify-against-server. orbpack.Expand doesn't aim for byte-identical
+// output (it re-serializes through a different YAML encoder and skips
+// parameter substitution), so the two are compared semantically: as
+// decoded YAML documents, not as strings.
+func diffAgainstServer(ctx context.Context, localOutput string) error {
+	response, err := orbValidateQuery(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !response.OrbConfig.Valid {
+		return response.processErrors()
+	}
+
+	equivalent, err := yamlSemanticallyEqual(response.OrbConfig.OutputYaml, localOutput)
+	if err != nil {
+		return errors.Wrap(err, "Unable to compare offline expansion against the server's OutputYaml")
+	}
+
+	if !equivalent {
+		return errors.New("offline expansion is not semantically equivalent to the server's OutputYaml")
+	}
+
+	Logger.Info("Offline expansion is semantically equivalent to the server's OutputYaml")
+	return nil
+}
+
+// yamlSemanticallyEqual reports whether a and b decode to the same YAML
+// document, ignoring key order, formatting and comments.
+func yamlSemanticallyEqual(a string, b string) (bool, error) {
+	var docA, docB interface{}
+
+	if err := yaml.Unmarshal([]byte(a), &docA); err != nil {
+		return false, errors.Wrap(err, "parsing server output")
+	}
+	if err := yaml.Unmarshal([]byte(b), &docB); err != nil {
+		return false, errors.Wrap(err, "parsing offline output")
+	}
+
+	return reflect.DeepEqual(docA, docB), nil
+}
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+// setupOrbReplayTest points the orb commands' GraphQL client at harPath in
+// replay mode, resets every orb command flag to its zero/default value, and
+// returns a cleanup func. Tests run serially against shared package-level
+// flag vars, so each test must reset them rather than relying on cobra's
+// flag defaults (which only apply when a command is actually Executed).
+func setupOrbReplayTest(t *testing.T, harPath string) {
+	t.Helper()
+
+	t.Setenv("CIRCLECI_CLI_HTTPRECORD_MODE", "replay")
+	t.Setenv("CIRCLECI_CLI_HTTPRECORD_PATH", harPath)
+	viper.Set("endpoint", "https://circleci.com/graphql-unstable")
+
+	scanSecrets = false
+	failOn = "verified"
+	offline = false
+	verifyAgainstServer = false
+	outputFormat = "text"
+
+	orbListNamespace = ""
+	orbListSearch = ""
+	orbListCertified = false
+	orbListUncertified = false
+	orbListLimit = 0
+	orbListPageSize = 20
+	orbListSort = "name"
+	orbListFormat = "text"
+}
+
+// writeTempOrbYaml writes contents to a temp orb.yml and points orbPath at
+// it, so validateOrb/expandOrb load exactly the source the HAR fixture's
+// variables hash was computed from.
+func writeTempOrbYaml(t *testing.T, contents string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "orb.yml")
+	if err := ioutil.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatalf("WriteFile() returned error: %s", err)
+	}
+	orbPath = path
+}
+
+// TestListOrbsAgainstHARFixture drives the real listOrbs RunE function
+// against the golden testdata/list_orbs.har fixture instead of a live
+// endpoint, exercising the replay path end to end.
+func TestListOrbsAgainstHARFixture(t *testing.T) {
+	setupOrbReplayTest(t, "testdata/list_orbs.har")
+
+	if err := listOrbs(nil, nil); err != nil {
+		t.Fatalf("listOrbs() returned error: %s", err)
+	}
+}
+
+// TestValidateOrbAgainstHARFixture drives the real validateOrb RunE
+// function against testdata/validate_orb.har.
+func TestValidateOrbAgainstHARFixture(t *testing.T) {
+	setupOrbReplayTest(t, "testdata/validate_orb.har")
+	writeTempOrbYaml(t, "version: 2.1\njobs: {}\n")
+
+	if err := validateOrb(nil, nil); err != nil {
+		t.Fatalf("validateOrb() returned error: %s", err)
+	}
+}
+
+// TestExpandOrbAgainstHARFixture drives the real expandOrb RunE function
+// against testdata/expand_orb.har.
+func TestExpandOrbAgainstHARFixture(t *testing.T) {
+	setupOrbReplayTest(t, "testdata/expand_orb.har")
+	writeTempOrbYaml(t, "version: 2.1\njobs: {}\n")
+
+	if err := expandOrb(nil, nil); err != nil {
+		t.Fatalf("expandOrb() returned error: %s", err)
+	}
+}
+
+// TestParseOrbRef covers the accepted "<namespace>/<orb>@<version>" syntax
+// shared by publish/promote/source/info, including the dev-tag version
+// form and the malformed inputs each of those commands needs to reject.
+func TestParseOrbRef(t *testing.T) {
+	cases := []struct {
+		name          string
+		ref           string
+		wantNamespace string
+		wantOrb       string
+		wantVersion   string
+		wantErr       bool
+	}{
+		{
+			name:          "valid ref",
+			ref:           "circleci/rollbar@1.0.1",
+			wantNamespace: "circleci",
+			wantOrb:       "rollbar",
+			wantVersion:   "1.0.1",
+		},
+		{
+			name:          "dev-tag version",
+			ref:           "circleci/rollbar@dev:my-branch",
+			wantNamespace: "circleci",
+			wantOrb:       "rollbar",
+			wantVersion:   "dev:my-branch",
+		},
+		{
+			name:    "missing version",
+			ref:     "circleci/rollbar",
+			wantErr: true,
+		},
+		{
+			name:    "malformed namespace",
+			ref:     "circleci/rollbar/extra@1.0.1",
+			wantErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			namespace, orb, version, err := parseOrbRef(c.ref)
+
+			if c.wantErr {
+				if err == nil {
+					t.Fatalf("parseOrbRef(%q) returned no error, want one", c.ref)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("parseOrbRef(%q) returned error: %s", c.ref, err)
+			}
+			if namespace != c.wantNamespace || orb != c.wantOrb || version != c.wantVersion {
+				t.Errorf("parseOrbRef(%q) = (%q, %q, %q), want (%q, %q, %q)",
+					c.ref, namespace, orb, version, c.wantNamespace, c.wantOrb, c.wantVersion)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package httprecord
+
+// The types below are the minimal subset of the HAR 1.2 schema
+// (http://www.softwareishard.com/blog/har-12-spec/) that httprecord needs;
+// fields outside of request/response bodies, method, URL and status are
+// intentionally omitted.
+
+type harDocument struct {
+	Log harLog `json:"log"`
+}
+
+type harLog struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+}
+
+type harRequest struct {
+	Method   string      `json:"method"`
+	URL      string      `json:"url"`
+	PostData harPostData `json:"postData"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harResponse struct {
+	Status  int        `json:"status"`
+	Content harContent `json:"content"`
+}
+
+type harContent struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
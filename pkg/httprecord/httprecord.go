@@ -0,0 +1,233 @@
+// Package httprecord lets the orb commands' GraphQL traffic be recorded to
+// a HAR 1.2 file and replayed from one later, so tests don't have to fire
+// real HTTP requests against a configured CircleCI endpoint.
+package httprecord
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Mode selects how a Transport behaves.
+type Mode int
+
+const (
+	// Off passes requests straight through to the wrapped RoundTripper.
+	Off Mode = iota
+	// Record passes requests through and additionally captures every
+	// request/response pair to a HAR file on Save.
+	Record
+	// Replay serves requests from a previously recorded HAR file and
+	// never touches the network.
+	Replay
+)
+
+// graphQLBody is the minimal shape of a machinebox/graphql request body,
+// used to compute a stable match key for an entry.
+type graphQLBody struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables"`
+}
+
+// operationName extracts the GraphQL operation name (the identifier after
+// "query " or "mutation ") from a query string, falling back to the whole
+// query if none is found.
+func operationName(query string) string {
+	for _, kw := range []string{"query ", "mutation "} {
+		idx := bytes.Index([]byte(query), []byte(kw))
+		if idx == -1 {
+			continue
+		}
+		rest := query[idx+len(kw):]
+		end := 0
+		for end < len(rest) && rest[end] != ' ' && rest[end] != '(' && rest[end] != '\n' && rest[end] != '\t' {
+			end++
+		}
+		if end > 0 {
+			return rest[:end]
+		}
+	}
+	return query
+}
+
+// matchKey returns the key used to correlate a request with a recorded HAR
+// entry: the GraphQL operation name plus a hash of its variables, so that
+// two calls to the same operation with different arguments (e.g. pagination
+// cursors) don't collide.
+func matchKey(body []byte) (string, error) {
+	var parsed graphQLBody
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return "", err
+	}
+
+	varsJSON, err := json.Marshal(parsed.Variables)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(varsJSON)
+	return fmt.Sprintf("%s:%s", operationName(parsed.Query), hex.EncodeToString(sum[:])), nil
+}
+
+// NewTransport returns an http.RoundTripper for the given mode. In Off mode
+// it is a pass-through to next. In Record mode every request/response pair
+// is buffered in memory and written to harPath by Save. In Replay mode
+// harPath is loaded up front and requests are served from it; a request
+// with no matching entry returns a 404.
+func NewTransport(mode Mode, harPath string, next http.RoundTripper) (http.RoundTripper, error) {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	switch mode {
+	case Record:
+		return &recordingTransport{next: next, harPath: harPath}, nil
+	case Replay:
+		return newReplayingTransport(harPath)
+	default:
+		return next, nil
+	}
+}
+
+type recordingTransport struct {
+	next    http.RoundTripper
+	harPath string
+	mu      sync.Mutex
+	entries []harEntry
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		reqBody, err = ioutil.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	started := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+
+	t.mu.Lock()
+	t.entries = append(t.entries, harEntry{
+		StartedDateTime: started.UTC().Format(time.RFC3339Nano),
+		Request: harRequest{
+			Method: req.Method,
+			URL:    req.URL.String(),
+			PostData: harPostData{
+				MimeType: req.Header.Get("Content-Type"),
+				Text:     string(reqBody),
+			},
+		},
+		Response: harResponse{
+			Status: resp.StatusCode,
+			Content: harContent{
+				MimeType: resp.Header.Get("Content-Type"),
+				Text:     string(respBody),
+			},
+		},
+	})
+	t.mu.Unlock()
+
+	return resp, nil
+}
+
+// Save writes every entry recorded so far to harPath as a HAR 1.2 log.
+func (t *recordingTransport) Save() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	doc := harDocument{Log: harLog{
+		Version: "1.2",
+		Creator: harCreator{Name: "circleci-cli", Version: "httprecord"},
+		Entries: t.entries,
+	}}
+
+	out, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(t.harPath, out, 0644)
+}
+
+type replayingTransport struct {
+	byKey map[string]harEntry
+}
+
+func newReplayingTransport(harPath string) (*replayingTransport, error) {
+	raw, err := ioutil.ReadFile(harPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc harDocument
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+
+	byKey := make(map[string]harEntry, len(doc.Log.Entries))
+	for _, entry := range doc.Log.Entries {
+		key, err := matchKey([]byte(entry.Request.PostData.Text))
+		if err != nil {
+			continue
+		}
+		byKey[key] = entry
+	}
+
+	return &replayingTransport{byKey: byKey}, nil
+}
+
+func (t *replayingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	key, err := matchKey(body)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, ok := t.byKey[key]
+	if !ok {
+		return &http.Response{
+			StatusCode: http.StatusNotFound,
+			Body:       ioutil.NopCloser(bytes.NewBufferString("httprecord: no matching HAR entry for request")),
+			Header:     make(http.Header),
+			Request:    req,
+		}, nil
+	}
+
+	resp := &http.Response{
+		StatusCode: entry.Response.Status,
+		Body:       ioutil.NopCloser(bytes.NewBufferString(entry.Response.Content.Text)),
+		Header:     make(http.Header),
+		Request:    req,
+	}
+	resp.Header.Set("Content-Type", entry.Response.Content.MimeType)
+	return resp, nil
+}
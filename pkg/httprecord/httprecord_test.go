@@ -0,0 +1,101 @@
+package httprecord
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func TestRecordThenReplay(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"data":{"orbs":{"totalCount":0}}}`))
+	}))
+	defer upstream.Close()
+
+	harPath := filepath.Join(t.TempDir(), "out.har")
+
+	recordTransport, err := NewTransport(Record, harPath, nil)
+	if err != nil {
+		t.Fatalf("NewTransport(Record) returned error: %s", err)
+	}
+
+	body := `{"query":"query ListOrbs ($after: String!) { orbs(first: 20, after: $after) { totalCount } }","variables":{"after":""}}`
+	req, err := http.NewRequest(http.MethodPost, upstream.URL, bytes.NewBufferString(body))
+	if err != nil {
+		t.Fatalf("NewRequest() returned error: %s", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := recordTransport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() returned error: %s", err)
+	}
+	resp.Body.Close()
+
+	recorder, ok := recordTransport.(interface{ Save() error })
+	if !ok {
+		t.Fatalf("recording transport does not expose Save()")
+	}
+	if err := recorder.Save(); err != nil {
+		t.Fatalf("Save() returned error: %s", err)
+	}
+
+	replayTransport, err := NewTransport(Replay, harPath, nil)
+	if err != nil {
+		t.Fatalf("NewTransport(Replay) returned error: %s", err)
+	}
+
+	replayReq, err := http.NewRequest(http.MethodPost, upstream.URL, bytes.NewBufferString(body))
+	if err != nil {
+		t.Fatalf("NewRequest() returned error: %s", err)
+	}
+
+	replayResp, err := replayTransport.RoundTrip(replayReq)
+	if err != nil {
+		t.Fatalf("RoundTrip() returned error: %s", err)
+	}
+	defer replayResp.Body.Close()
+
+	got, err := ioutil.ReadAll(replayResp.Body)
+	if err != nil {
+		t.Fatalf("ReadAll() returned error: %s", err)
+	}
+
+	want := `{"data":{"orbs":{"totalCount":0}}}`
+	if string(got) != want {
+		t.Errorf("expected replayed body %q, got %q", want, string(got))
+	}
+}
+
+func TestReplayMiss(t *testing.T) {
+	harPath := filepath.Join(t.TempDir(), "empty.har")
+	if err := ioutil.WriteFile(harPath, []byte(`{"log":{"version":"1.2","creator":{"name":"x","version":"x"},"entries":[]}}`), 0644); err != nil {
+		t.Fatalf("WriteFile() returned error: %s", err)
+	}
+
+	transport, err := NewTransport(Replay, harPath, nil)
+	if err != nil {
+		t.Fatalf("NewTransport(Replay) returned error: %s", err)
+	}
+
+	body := `{"query":"query Unknown { foo }","variables":{}}`
+	req, err := http.NewRequest(http.MethodPost, "https://example.com/graphql", bytes.NewBufferString(body))
+	if err != nil {
+		t.Fatalf("NewRequest() returned error: %s", err)
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip() returned error: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404 for unmatched request, got %d", resp.StatusCode)
+	}
+}
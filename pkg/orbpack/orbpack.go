@@ -0,0 +1,141 @@
+// Package orbpack expands an orb.yml's `orbs:` stanza on the client, so
+// that orb references can be resolved without a round-trip to the
+// orbConfig GraphQL field. It inlines referenced jobs, commands and
+// executors the same way the API does, but does not perform parameter
+// substitution or step/alias inlining, and re-serializes through
+// gopkg.in/yaml.v2 (which reorders map keys and drops comments). Its
+// output is therefore semantically, not byte-for-byte, equivalent to the
+// server's OutputYaml — enough to unblock air-gapped use and sub-second
+// IDE expansion, not to replace --verify-against-server with a text diff.
+//
+// Because parameter substitution isn't implemented, Expand refuses to
+// silently hand back config with a dangling "<< parameters.* >>"
+// placeholder: inlineOrb fails fast on any inlined job/command/executor
+// that still references one, rather than shipping it out unresolved.
+package orbpack
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// unresolvedParamPattern matches an orb parameter placeholder like
+// "<< parameters.tag >>" that inlineOrb leaves untouched, since it does
+// not perform parameter substitution.
+var unresolvedParamPattern = regexp.MustCompile(`<<\s*parameters\.[A-Za-z0-9_-]+\s*>>`)
+
+// unresolvedParameterRefs walks a YAML-decoded job/command/executor body
+// (as produced by yaml.Unmarshal into interface{}) looking for every
+// "<< parameters.* >>" placeholder it contains.
+func unresolvedParameterRefs(v interface{}) []string {
+	var refs []string
+	switch val := v.(type) {
+	case string:
+		refs = append(refs, unresolvedParamPattern.FindAllString(val, -1)...)
+	case map[interface{}]interface{}:
+		for _, child := range val {
+			refs = append(refs, unresolvedParameterRefs(child)...)
+		}
+	case []interface{}:
+		for _, child := range val {
+			refs = append(refs, unresolvedParameterRefs(child)...)
+		}
+	}
+	return refs
+}
+
+// ResolveFunc returns the source YAML of the orb referenced by ref (a
+// "namespace/orb@version" string). Callers decide where that comes from:
+// a local cache directory, a single GraphQL fetch, or both.
+type ResolveFunc func(ref string) (string, error)
+
+// Expand inlines every entry of source's `orbs:` stanza using resolve, and
+// returns the resulting YAML with no remaining `orbs:` references. It
+// leaves every other top-level key untouched.
+func Expand(source string, resolve ResolveFunc) (string, error) {
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal([]byte(source), &doc); err != nil {
+		return "", errors.Wrap(err, "parsing orb.yml")
+	}
+
+	rawOrbs, ok := doc["orbs"]
+	if !ok {
+		// Nothing to inline.
+		out, err := yaml.Marshal(doc)
+		if err != nil {
+			return "", err
+		}
+		return string(out), nil
+	}
+
+	orbs, ok := rawOrbs.(map[interface{}]interface{})
+	if !ok {
+		return "", fmt.Errorf("orbs: stanza is not a mapping of alias to orb reference")
+	}
+
+	for aliasRaw, refRaw := range orbs {
+		alias, ok := aliasRaw.(string)
+		if !ok {
+			return "", fmt.Errorf("orb alias %v is not a string", aliasRaw)
+		}
+
+		ref, ok := refRaw.(string)
+		if !ok {
+			return "", fmt.Errorf("orb reference for alias %q is not a string", alias)
+		}
+
+		resolved, err := resolve(ref)
+		if err != nil {
+			return "", errors.Wrapf(err, "resolving %s", ref)
+		}
+
+		if err := inlineOrb(doc, alias, resolved); err != nil {
+			return "", errors.Wrapf(err, "inlining %s as %q", ref, alias)
+		}
+	}
+
+	delete(doc, "orbs")
+
+	out, err := yaml.Marshal(doc)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// inlineOrb merges the jobs, commands and executors of the orb whose
+// source is orbYaml into doc, namespacing each element as "<alias>/<name>"
+// the same way a job import like "my-orb/build" resolves on the server.
+func inlineOrb(doc map[string]interface{}, alias string, orbYaml string) error {
+	var orbDoc map[string]interface{}
+	if err := yaml.Unmarshal([]byte(orbYaml), &orbDoc); err != nil {
+		return err
+	}
+
+	for _, section := range []string{"jobs", "commands", "executors"} {
+		elements, ok := orbDoc[section].(map[interface{}]interface{})
+		if !ok {
+			continue
+		}
+
+		dest, ok := doc[section].(map[interface{}]interface{})
+		if !ok {
+			dest = map[interface{}]interface{}{}
+		}
+
+		for name, body := range elements {
+			if refs := unresolvedParameterRefs(body); len(refs) > 0 {
+				return fmt.Errorf("%s/%v references %s, which orbpack cannot substitute (parameter substitution is not implemented)", alias, name, strings.Join(refs, ", "))
+			}
+			dest[fmt.Sprintf("%s/%v", alias, name)] = body
+		}
+
+		doc[section] = dest
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package orbpack
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExpandInlinesReferencedOrb(t *testing.T) {
+	source := `
+version: 2.1
+orbs:
+  hello: my-ns/hello@1.0.0
+jobs:
+  build:
+    docker: []
+`
+
+	resolve := func(ref string) (string, error) {
+		if ref != "my-ns/hello@1.0.0" {
+			t.Fatalf("unexpected ref %q", ref)
+		}
+		return `
+jobs:
+  greet:
+    docker: []
+`, nil
+	}
+
+	out, err := Expand(source, resolve)
+	if err != nil {
+		t.Fatalf("Expand() returned error: %s", err)
+	}
+
+	if strings.Contains(out, "orbs:") {
+		t.Errorf("expected orbs: stanza to be removed, got:\n%s", out)
+	}
+
+	if !strings.Contains(out, "hello/greet") {
+		t.Errorf("expected inlined job hello/greet, got:\n%s", out)
+	}
+
+	if !strings.Contains(out, "build") {
+		t.Errorf("expected original build job to survive, got:\n%s", out)
+	}
+}
+
+func TestExpandFailsOnUnresolvedParameter(t *testing.T) {
+	source := `
+version: 2.1
+orbs:
+  hello: my-ns/hello@1.0.0
+jobs:
+  build:
+    docker: []
+`
+
+	resolve := func(ref string) (string, error) {
+		return `
+jobs:
+  greet:
+    docker:
+      - image: "<< parameters.image >>"
+`, nil
+	}
+
+	_, err := Expand(source, resolve)
+	if err == nil {
+		t.Fatal("Expand() returned no error for a job with an unresolved parameter placeholder")
+	}
+
+	if !strings.Contains(err.Error(), "<< parameters.image >>") {
+		t.Errorf("expected error to name the unresolved placeholder, got: %s", err)
+	}
+}
+
+func TestExpandNoOrbsStanza(t *testing.T) {
+	source := "version: 2.1\njobs:\n  build:\n    docker: []\n"
+
+	out, err := Expand(source, func(ref string) (string, error) {
+		t.Fatalf("resolve should not be called when there is no orbs: stanza")
+		return "", nil
+	})
+	if err != nil {
+		t.Fatalf("Expand() returned error: %s", err)
+	}
+
+	if !strings.Contains(out, "build") {
+		t.Errorf("expected original build job to survive, got:\n%s", out)
+	}
+}
@@ -0,0 +1,89 @@
+// Package orbscan detects secrets accidentally committed to orb source
+// before it is sent to the CircleCI API. Orbs are published globally, so a
+// leaked credential in orb.yml is effectively public the moment `orb
+// publish` succeeds.
+package orbscan
+
+import (
+	"bufio"
+	"context"
+	"io"
+)
+
+// Finding is a single potential secret detected in an orb source file.
+type Finding struct {
+	RuleID   string
+	Provider string
+	Line     int
+	Match    string
+	Verified bool
+}
+
+// Scanner detects secrets in orb source and, where possible, verifies them
+// against the issuing provider's API.
+type Scanner interface {
+	// Detect scans r line by line and returns every match against the
+	// scanner's rule set.
+	Detect(r io.Reader) ([]Finding, error)
+
+	// Verify calls out to the provider that issued the credential behind
+	// f to check whether it is still live. It returns false for rules
+	// that have no verifier, or when verification fails for any reason.
+	Verify(ctx context.Context, f Finding) bool
+}
+
+// defaultScanner is the built-in Scanner backed by the rule registry in
+// rules.go.
+type defaultScanner struct {
+	rules []Rule
+}
+
+// NewScanner returns a Scanner configured with the default rule registry.
+func NewScanner() Scanner {
+	return &defaultScanner{rules: DefaultRules}
+}
+
+func (s *defaultScanner) Detect(r io.Reader) ([]Finding, error) {
+	var findings []Finding
+
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := scanner.Text()
+
+		for _, rule := range s.rules {
+			matches := rule.Pattern.FindAllString(line, -1)
+			for _, m := range matches {
+				if rule.MinEntropy > 0 && shannonEntropy(m) < rule.MinEntropy {
+					continue
+				}
+				findings = append(findings, Finding{
+					RuleID:   rule.ID,
+					Provider: rule.Provider,
+					Line:     lineNo,
+					Match:    m,
+				})
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return findings, nil
+}
+
+func (s *defaultScanner) Verify(ctx context.Context, f Finding) bool {
+	verify, ok := verifiers[f.RuleID]
+	if !ok {
+		return false
+	}
+
+	ok, err := verify(ctx, f.Match)
+	if err != nil {
+		return false
+	}
+	return ok
+}
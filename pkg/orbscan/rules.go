@@ -0,0 +1,305 @@
+package orbscan
+
+import (
+	"math"
+	"regexp"
+)
+
+// Rule describes a single detector: a regular expression that flags
+// candidate secrets, optionally gated by a minimum Shannon entropy to cut
+// down on false positives for generic-looking tokens.
+type Rule struct {
+	ID         string
+	Provider   string
+	Pattern    *regexp.Regexp
+	MinEntropy float64
+}
+
+// DefaultRules is the built-in registry of secret detectors, covering
+// AWS, GCP, Azure, GitHub, CircleCI, Slack, npm, Docker Hub, Stripe,
+// Twilio, SendGrid, Mailgun, Mailchimp, Heroku, PyPI, RubyGems, Shopify,
+// Square, New Relic, Algolia, Cloudflare, DigitalOcean, Linear, OpenAI,
+// Anthropic, Postman, Sentry, HashiCorp Vault, PlanetScale, Fastly,
+// Bitbucket, Asana, Telegram, Discord and Terraform Cloud, plus a few
+// generic, provider-agnostic formats (PEM private keys, encrypted SSH key
+// passphrases, JWTs, `key: value`-shaped assignments). It favors precision
+// over exhaustiveness: each entry targets a provider's well-documented
+// token format rather than a generic high-entropy-string heuristic, which
+// keeps false positives in orb.yml (itself mostly low-entropy YAML) low.
+var DefaultRules = []Rule{
+	{
+		ID:       "aws-access-key-id",
+		Provider: "aws",
+		Pattern:  regexp.MustCompile(`\b(A3T[A-Z0-9]|AKIA|AGPA|AIDA|AROA|AIPA|ANPA|ANVA|ASIA)[A-Z0-9]{16}\b`),
+	},
+	{
+		ID:         "aws-secret-access-key",
+		Provider:   "aws",
+		Pattern:    regexp.MustCompile(`\b[A-Za-z0-9/+=]{40}\b`),
+		MinEntropy: 4.5,
+	},
+	{
+		ID:       "gcp-service-account",
+		Provider: "gcp",
+		Pattern:  regexp.MustCompile(`"type": "service_account"`),
+	},
+	{
+		ID:       "github-pat",
+		Provider: "github",
+		Pattern:  regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36}\b`),
+	},
+	{
+		ID:       "circleci-api-token",
+		Provider: "circleci",
+		Pattern:  regexp.MustCompile(`\bCCIPAT_[A-Za-z0-9]{22,}\b`),
+	},
+	{
+		ID:       "slack-webhook",
+		Provider: "slack",
+		Pattern:  regexp.MustCompile(`https://hooks\.slack\.com/services/T[A-Z0-9]+/B[A-Z0-9]+/[A-Za-z0-9]+`),
+	},
+	{
+		ID:       "slack-token",
+		Provider: "slack",
+		Pattern:  regexp.MustCompile(`\bxox[baprs]-[A-Za-z0-9-]{10,72}\b`),
+	},
+	{
+		ID:       "private-key",
+		Provider: "generic",
+		Pattern:  regexp.MustCompile(`-----BEGIN (RSA|OPENSSH|DSA|EC|PGP) PRIVATE KEY-----`),
+	},
+	{
+		ID:         "jwt",
+		Provider:   "generic",
+		Pattern:    regexp.MustCompile(`\bey[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}\b`),
+		MinEntropy: 3.5,
+	},
+	{
+		ID:       "npm-token",
+		Provider: "npm",
+		Pattern:  regexp.MustCompile(`\bnpm_[A-Za-z0-9]{36}\b`),
+	},
+	{
+		ID:       "docker-hub-pat",
+		Provider: "dockerhub",
+		Pattern:  regexp.MustCompile(`\bdckr_pat_[A-Za-z0-9_-]{27}\b`),
+	},
+	{
+		ID:       "stripe-live-secret-key",
+		Provider: "stripe",
+		Pattern:  regexp.MustCompile(`\bsk_live_[A-Za-z0-9]{24,}\b`),
+	},
+	{
+		ID:       "stripe-restricted-key",
+		Provider: "stripe",
+		Pattern:  regexp.MustCompile(`\brk_live_[A-Za-z0-9]{24,}\b`),
+	},
+	{
+		ID:       "twilio-account-sid",
+		Provider: "twilio",
+		Pattern:  regexp.MustCompile(`\bAC[a-fA-F0-9]{32}\b`),
+	},
+	{
+		ID:       "twilio-api-key",
+		Provider: "twilio",
+		Pattern:  regexp.MustCompile(`\bSK[a-fA-F0-9]{32}\b`),
+	},
+	{
+		ID:       "sendgrid-api-key",
+		Provider: "sendgrid",
+		Pattern:  regexp.MustCompile(`\bSG\.[A-Za-z0-9_-]{22}\.[A-Za-z0-9_-]{43}\b`),
+	},
+	{
+		ID:       "mailgun-api-key",
+		Provider: "mailgun",
+		Pattern:  regexp.MustCompile(`\bkey-[a-f0-9]{32}\b`),
+	},
+	{
+		ID:       "mailchimp-api-key",
+		Provider: "mailchimp",
+		Pattern:  regexp.MustCompile(`\b[a-f0-9]{32}-us[0-9]{1,2}\b`),
+	},
+	{
+		ID:       "azure-storage-account-key",
+		Provider: "azure",
+		Pattern:  regexp.MustCompile(`\b[A-Za-z0-9+/]{86}==\b`),
+	},
+	{
+		ID:         "azure-client-secret",
+		Provider:   "azure",
+		Pattern:    regexp.MustCompile(`(?i)(azure|arm)[a-z_-]*client[_-]?secret\s*[:=]\s*['"][A-Za-z0-9_~.\-]{34,40}['"]`),
+		MinEntropy: 3.5,
+	},
+	{
+		ID:       "gcp-api-key",
+		Provider: "gcp",
+		Pattern:  regexp.MustCompile(`\bAIza[A-Za-z0-9_-]{35}\b`),
+	},
+	{
+		ID:       "gcp-oauth-client-secret",
+		Provider: "gcp",
+		Pattern:  regexp.MustCompile(`\bGOCSPX-[A-Za-z0-9_-]{28}\b`),
+	},
+	{
+		ID:       "heroku-api-key",
+		Provider: "heroku",
+		Pattern:  regexp.MustCompile(`(?i)heroku[a-z_-]*\s*[:=]\s*['"]?[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}['"]?`),
+	},
+	{
+		ID:       "pypi-upload-token",
+		Provider: "pypi",
+		Pattern:  regexp.MustCompile(`\bpypi-AgEIcHlwaS5vcmc[A-Za-z0-9_-]{50,}\b`),
+	},
+	{
+		ID:       "rubygems-api-key",
+		Provider: "rubygems",
+		Pattern:  regexp.MustCompile(`\brubygems_[a-f0-9]{48}\b`),
+	},
+	{
+		ID:       "shopify-access-token",
+		Provider: "shopify",
+		Pattern:  regexp.MustCompile(`\bshpat_[a-fA-F0-9]{32}\b`),
+	},
+	{
+		ID:       "shopify-custom-app-token",
+		Provider: "shopify",
+		Pattern:  regexp.MustCompile(`\bshpca_[a-fA-F0-9]{32}\b`),
+	},
+	{
+		ID:       "square-access-token",
+		Provider: "square",
+		Pattern:  regexp.MustCompile(`\bsq0atp-[A-Za-z0-9_-]{22}\b`),
+	},
+	{
+		ID:       "square-oauth-secret",
+		Provider: "square",
+		Pattern:  regexp.MustCompile(`\bsq0csp-[A-Za-z0-9_-]{43}\b`),
+	},
+	{
+		ID:       "new-relic-api-key",
+		Provider: "newrelic",
+		Pattern:  regexp.MustCompile(`\bNRAK-[A-Z0-9]{27}\b`),
+	},
+	{
+		ID:       "algolia-admin-key",
+		Provider: "algolia",
+		Pattern:  regexp.MustCompile(`(?i)algolia[a-z_-]*(admin|api)[a-z_-]*\s*[:=]\s*['"][a-f0-9]{32}['"]`),
+	},
+	{
+		ID:       "cloudflare-api-token",
+		Provider: "cloudflare",
+		Pattern:  regexp.MustCompile(`(?i)cloudflare[a-z_-]*token\s*[:=]\s*['"][A-Za-z0-9_-]{40}['"]`),
+	},
+	{
+		ID:       "digitalocean-pat",
+		Provider: "digitalocean",
+		Pattern:  regexp.MustCompile(`\bdop_v1_[a-f0-9]{64}\b`),
+	},
+	{
+		ID:       "linear-api-key",
+		Provider: "linear",
+		Pattern:  regexp.MustCompile(`\blin_api_[A-Za-z0-9]{40}\b`),
+	},
+	{
+		ID:       "openai-api-key",
+		Provider: "openai",
+		Pattern:  regexp.MustCompile(`\bsk-[A-Za-z0-9]{20,}T3BlbkFJ[A-Za-z0-9]{20,}\b`),
+	},
+	{
+		ID:       "anthropic-api-key",
+		Provider: "anthropic",
+		Pattern:  regexp.MustCompile(`\bsk-ant-[A-Za-z0-9_-]{90,}\b`),
+	},
+	{
+		ID:       "postman-api-key",
+		Provider: "postman",
+		Pattern:  regexp.MustCompile(`\bPMAK-[a-f0-9]{24}-[a-f0-9]{34}\b`),
+	},
+	{
+		ID:       "sentry-auth-token",
+		Provider: "sentry",
+		Pattern:  regexp.MustCompile(`\bsntrys_[A-Za-z0-9_=]{40,}\b`),
+	},
+	{
+		ID:       "hashicorp-vault-token",
+		Provider: "vault",
+		Pattern:  regexp.MustCompile(`\bhvs\.[A-Za-z0-9_-]{24,}\b`),
+	},
+	{
+		ID:       "planetscale-password",
+		Provider: "planetscale",
+		Pattern:  regexp.MustCompile(`\bpscale_pw_[A-Za-z0-9_]{43}\b`),
+	},
+	{
+		ID:       "planetscale-api-token",
+		Provider: "planetscale",
+		Pattern:  regexp.MustCompile(`\bpscale_tkn_[A-Za-z0-9_]{43}\b`),
+	},
+	{
+		ID:       "fastly-api-token",
+		Provider: "fastly",
+		Pattern:  regexp.MustCompile(`(?i)fastly[a-z_-]*token\s*[:=]\s*['"][A-Za-z0-9_-]{32}['"]`),
+	},
+	{
+		ID:       "bitbucket-app-password",
+		Provider: "bitbucket",
+		Pattern:  regexp.MustCompile(`\bATBB[A-Za-z0-9]{32}\b`),
+	},
+	{
+		ID:       "asana-pat",
+		Provider: "asana",
+		Pattern:  regexp.MustCompile(`\b[0-9]/[0-9]{16,}:[a-f0-9]{32}\b`),
+	},
+	{
+		ID:       "telegram-bot-token",
+		Provider: "telegram",
+		Pattern:  regexp.MustCompile(`\b[0-9]{8,10}:AA[A-Za-z0-9_-]{33}\b`),
+	},
+	{
+		ID:       "discord-bot-token",
+		Provider: "discord",
+		Pattern:  regexp.MustCompile(`\b[MN][A-Za-z0-9_-]{23,25}\.[A-Za-z0-9_-]{6}\.[A-Za-z0-9_-]{27,38}\b`),
+	},
+	{
+		ID:       "slack-app-token",
+		Provider: "slack",
+		Pattern:  regexp.MustCompile(`\bxapp-[0-9]-[A-Z0-9]+-[0-9]+-[a-f0-9]{64}\b`),
+	},
+	{
+		ID:       "terraform-cloud-token",
+		Provider: "terraform",
+		Pattern:  regexp.MustCompile(`\b[A-Za-z0-9]{14}\.atlasv1\.[A-Za-z0-9_-]{60,90}\b`),
+	},
+	{
+		ID:       "ssh-private-key-passphrase",
+		Provider: "generic",
+		Pattern:  regexp.MustCompile(`Proc-Type:\s*4,ENCRYPTED`),
+	},
+	{
+		ID:       "generic-api-key-assignment",
+		Provider: "generic",
+		Pattern:  regexp.MustCompile(`(?i)(api[_-]?key|secret|token|password)\s*[:=]\s*['"][A-Za-z0-9/+=_-]{16,}['"]`),
+	},
+}
+
+// shannonEntropy returns the Shannon entropy of s in bits per character,
+// used to filter MinEntropy rules away from low-entropy look-alikes (e.g.
+// a 40-char base64 placeholder of all the same character).
+func shannonEntropy(s string) float64 {
+	if len(s) == 0 {
+		return 0
+	}
+
+	counts := make(map[rune]int)
+	for _, r := range s {
+		counts[r]++
+	}
+
+	var entropy float64
+	length := float64(len(s))
+	for _, c := range counts {
+		p := float64(c) / length
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
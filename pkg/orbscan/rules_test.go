@@ -0,0 +1,32 @@
+package orbscan
+
+import "testing"
+
+func TestShannonEntropy(t *testing.T) {
+	cases := []struct {
+		name string
+		in   string
+		want float64
+	}{
+		{"empty", "", 0},
+		{"single repeated character", "aaaaaaaa", 0},
+		{"two characters split evenly", "aabb", 1},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := shannonEntropy(c.in); got != c.want {
+				t.Errorf("shannonEntropy(%q) = %v, want %v", c.in, got, c.want)
+			}
+		})
+	}
+}
+
+func TestShannonEntropyIncreasesWithVariety(t *testing.T) {
+	low := shannonEntropy("aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa")
+	high := shannonEntropy("xQ7mK2pL9vRtN4sW8yB1cF6hJ0dZgA3eU5iO9kXn")
+
+	if !(high > low) {
+		t.Errorf("expected a varied 40-char string (entropy %v) to score higher than a repeated one (entropy %v)", high, low)
+	}
+}
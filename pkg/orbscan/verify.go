@@ -0,0 +1,64 @@
+package orbscan
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// verifyFunc calls out to the provider that is believed to have issued
+// match to check whether the credential is still live.
+type verifyFunc func(ctx context.Context, match string) (bool, error)
+
+// httpClient issues every verifier's request. It's a var, rather than a
+// direct reference to http.DefaultClient, so tests can point verification
+// at a local httptest.Server instead of the real provider APIs.
+var httpClient = http.DefaultClient
+
+// verifiers maps a rule ID to the verifier that can confirm a match
+// against it. Rules with no entry here can only ever be reported as
+// "unverified".
+var verifiers = map[string]verifyFunc{
+	"github-pat":    verifyGitHubToken,
+	"slack-token":   verifySlackToken,
+	"slack-webhook": verifySlackWebhook,
+}
+
+func verifyGitHubToken(ctx context.Context, token string) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.github.com/user", nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("token %s", token))
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	return resp.StatusCode == http.StatusOK, nil
+}
+
+func verifySlackToken(ctx context.Context, token string) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://slack.com/api/auth.test", nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	return resp.StatusCode == http.StatusOK, nil
+}
+
+func verifySlackWebhook(ctx context.Context, url string) (bool, error) {
+	// Posting to a webhook to "verify" it would fire a real message into
+	// the channel it's bound to, so webhooks are reported as found but
+	// never verified.
+	return false, nil
+}
@@ -0,0 +1,111 @@
+package orbscan
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// withStubHTTPClient points httpClient at server for the duration of the
+// test, restoring the original client on cleanup.
+func withStubHTTPClient(t *testing.T, server *httptest.Server) {
+	t.Helper()
+	original := httpClient
+	httpClient = &http.Client{
+		Transport: roundTripToServer{server: server},
+	}
+	t.Cleanup(func() { httpClient = original })
+}
+
+// roundTripToServer redirects every request to server, regardless of the
+// original request's host, so the real verifyGitHubToken/verifySlackToken
+// code paths can be exercised against a local server instead of the real
+// provider APIs.
+type roundTripToServer struct {
+	server *httptest.Server
+}
+
+func (rt roundTripToServer) RoundTrip(req *http.Request) (*http.Response, error) {
+	clone := req.Clone(req.Context())
+	serverURL, err := http.NewRequest(req.Method, rt.server.URL+req.URL.Path, nil)
+	if err != nil {
+		return nil, err
+	}
+	clone.URL = serverURL.URL
+	clone.Host = serverURL.Host
+	return http.DefaultTransport.RoundTrip(clone)
+}
+
+func TestVerifyGitHubTokenStatusCodes(t *testing.T) {
+	cases := []struct {
+		name       string
+		statusCode int
+		want       bool
+	}{
+		{"live token", http.StatusOK, true},
+		{"revoked token", http.StatusUnauthorized, false},
+		{"rate limited", http.StatusForbidden, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(c.statusCode)
+			}))
+			defer server.Close()
+			withStubHTTPClient(t, server)
+
+			ok, err := verifyGitHubToken(context.Background(), "ghp_faketoken")
+			if err != nil {
+				t.Fatalf("verifyGitHubToken() returned error: %s", err)
+			}
+			if ok != c.want {
+				t.Errorf("verifyGitHubToken() = %v for status %d, want %v", ok, c.statusCode, c.want)
+			}
+		})
+	}
+}
+
+func TestVerifySlackTokenStatusCodes(t *testing.T) {
+	cases := []struct {
+		name       string
+		statusCode int
+		want       bool
+	}{
+		{"live token", http.StatusOK, true},
+		{"revoked token", http.StatusUnauthorized, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(c.statusCode)
+			}))
+			defer server.Close()
+			withStubHTTPClient(t, server)
+
+			ok, err := verifySlackToken(context.Background(), "xoxb-faketoken")
+			if err != nil {
+				t.Fatalf("verifySlackToken() returned error: %s", err)
+			}
+			if ok != c.want {
+				t.Errorf("verifySlackToken() = %v for status %d, want %v", ok, c.statusCode, c.want)
+			}
+		})
+	}
+}
+
+func TestScannerVerifyPropagatesStatusCode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	withStubHTTPClient(t, server)
+
+	scanner := NewScanner()
+	ok := scanner.Verify(context.Background(), Finding{RuleID: "github-pat", Match: "ghp_faketoken"})
+	if !ok {
+		t.Errorf("Scanner.Verify() = false for a 200 response, want true")
+	}
+}